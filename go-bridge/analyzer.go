@@ -0,0 +1,33 @@
+package main
+
+import (
+    "context"
+    "fmt"
+)
+
+// Analyzer produces a CrodAnalysis for a single message. Backends register
+// themselves in init() via RegisterAnalyzer so they can be selected at
+// runtime without the rest of the bridge knowing about the concrete type.
+type Analyzer interface {
+    Name() string
+    Analyze(ctx context.Context, msg Message) (*CrodAnalysis, error)
+}
+
+var analyzerRegistry = map[string]Analyzer{}
+
+// RegisterAnalyzer adds a backend to the registry. Backends call this from
+// their own init() function, mirroring the database/sql driver pattern so
+// third parties can add a backend with a plain underscore import.
+func RegisterAnalyzer(a Analyzer) {
+    analyzerRegistry[a.Name()] = a
+}
+
+func getAnalyzer(name string) (Analyzer, error) {
+    a, ok := analyzerRegistry[name]
+    if !ok {
+        return nil, fmt.Errorf("unknown analyzer backend %q", name)
+    }
+    return a, nil
+}
+
+var defaultBackend = getEnv("ANALYZER_DEFAULT", "http")
@@ -0,0 +1,73 @@
+package main
+
+import (
+    "context"
+    "sync"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+
+    "crod-go-bridge/crodpb"
+)
+
+// grpcAnalyzer talks to a CROD instance exposing the CrodService gRPC API
+// (see crodpb/crod.proto), for deployments that run CROD as a gRPC service
+// instead of (or alongside) the HTTP API.
+type grpcAnalyzer struct {
+    addr string
+
+    mu     sync.Mutex
+    client crodpb.CrodServiceClient
+}
+
+func (a *grpcAnalyzer) Name() string { return "grpc" }
+
+func (a *grpcAnalyzer) dial() (crodpb.CrodServiceClient, error) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    if a.client != nil {
+        return a.client, nil
+    }
+    conn, err := grpc.NewClient(a.addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        return nil, err
+    }
+    a.client = crodpb.NewCrodServiceClient(conn)
+    return a.client, nil
+}
+
+func (a *grpcAnalyzer) Analyze(ctx context.Context, msg Message) (*CrodAnalysis, error) {
+    client, err := a.dial()
+    if err != nil {
+        return nil, err
+    }
+
+    resp, err := client.Analyze(ctx, &crodpb.AnalyzeRequest{
+        Message: msg.Content,
+        Context: msg.Context,
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    var analysis CrodAnalysis
+    analysis.OriginalMessage = msg.Content
+    analysis.CrodAnalysis.Confidence = resp.Confidence
+    analysis.CrodAnalysis.Mood = resp.Mood
+    analysis.CrodAnalysis.Intent = resp.Intent
+    analysis.CrodAnalysis.NeuronsActivated = int(resp.NeuronsActivated)
+    analysis.Suggestions.Tone = resp.Tone
+    analysis.Suggestions.FocusOn = resp.FocusOn
+    analysis.Suggestions.Avoid = resp.Avoid
+    return &analysis, nil
+}
+
+func init() {
+    addr := getEnv("CROD_GRPC_ADDR", "")
+    if addr == "" {
+        // No gRPC endpoint configured; skip registration rather than dial
+        // a backend nobody asked for.
+        return
+    }
+    RegisterAnalyzer(&grpcAnalyzer{addr: addr})
+}
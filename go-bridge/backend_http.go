@@ -0,0 +1,18 @@
+package main
+
+import "context"
+
+// httpAnalyzer is the original backend: it calls the CROD HTTP API and
+// waits for the final streamed delta.
+type httpAnalyzer struct{}
+
+func (httpAnalyzer) Name() string { return "http" }
+
+func (httpAnalyzer) Analyze(ctx context.Context, msg Message) (*CrodAnalysis, error) {
+    deltas, errs := analyzeWithCROD(ctx, msg)
+    return lastDelta(deltas, errs)
+}
+
+func init() {
+    RegisterAnalyzer(httpAnalyzer{})
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+    "context"
+    "strings"
+)
+
+// noopAnalyzer is a local heuristic backend for offline development: it
+// never calls out to CROD, so the bridge keeps working when CROD isn't
+// running. Mood is guessed from simple keyword matching; intent is always
+// reported as "unknown".
+type noopAnalyzer struct{}
+
+func (noopAnalyzer) Name() string { return "noop" }
+
+func (noopAnalyzer) Analyze(_ context.Context, msg Message) (*CrodAnalysis, error) {
+    var analysis CrodAnalysis
+    analysis.OriginalMessage = msg.Content
+    analysis.CrodAnalysis.Confidence = 0.1
+    analysis.CrodAnalysis.Mood = guessMood(msg.Content)
+    analysis.CrodAnalysis.Intent = "unknown"
+    analysis.CrodAnalysis.NeuronsActivated = 0
+    analysis.Suggestions.Tone = "neutral"
+    return &analysis, nil
+}
+
+func guessMood(content string) string {
+    lower := strings.ToLower(content)
+    switch {
+    case strings.ContainsAny(lower, "!") || strings.Contains(lower, "great") || strings.Contains(lower, "awesome"):
+        return "positive"
+    case strings.Contains(lower, "error") || strings.Contains(lower, "broken") || strings.Contains(lower, "angry"):
+        return "negative"
+    default:
+        return "neutral"
+    }
+}
+
+func init() {
+    RegisterAnalyzer(noopAnalyzer{})
+}
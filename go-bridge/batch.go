@@ -0,0 +1,105 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "net/http"
+    "runtime"
+    "strconv"
+    "sync"
+)
+
+type BatchRequest struct {
+    Messages []Message `json:"messages" validate:"required,min=1,dive"`
+}
+
+type BatchItemResult struct {
+    Analysis *CrodAnalysis `json:"analysis,omitempty"`
+    Error    string        `json:"error,omitempty"`
+}
+
+// bridgeWorkers bounds how many messages in a batch are analyzed
+// concurrently; BRIDGE_WORKERS lets operators tune it for their CROD
+// instance's capacity.
+var bridgeWorkers = getEnvInt("BRIDGE_WORKERS", runtime.GOMAXPROCS(0)*4)
+
+// handleProcessBatch fans a list of messages out to CROD across a bounded
+// worker pool and returns their results in the same order they were
+// submitted.
+func handleProcessBatch(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var req BatchRequest
+    if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+        http.Error(w, err.Error(), http.StatusBadRequest)
+        return
+    }
+    if err := validate.Struct(req); err != nil {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(fieldErrorsResponse(err))
+        return
+    }
+
+    results := make([]BatchItemResult, len(req.Messages))
+    indexes := make(chan int)
+
+    workers := bridgeWorkers
+    if workers < 1 {
+        workers = 1
+    }
+    if workers > len(req.Messages) {
+        workers = len(req.Messages)
+    }
+
+    var wg sync.WaitGroup
+    for i := 0; i < workers; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for idx := range indexes {
+                results[idx] = analyzeBatchItem(r.Context(), req.Messages[idx])
+            }
+        }()
+    }
+
+    for i := range req.Messages {
+        indexes <- i
+    }
+    close(indexes)
+    wg.Wait()
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+}
+
+func analyzeBatchItem(ctx context.Context, msg Message) BatchItemResult {
+    backendName := msg.Backend
+    if backendName == "" {
+        backendName = defaultBackend
+    }
+    analyzer, err := getAnalyzer(backendName)
+    if err != nil {
+        return BatchItemResult{Error: err.Error()}
+    }
+    analysis, err := analyzer.Analyze(ctx, msg)
+    if err != nil {
+        return BatchItemResult{Error: err.Error()}
+    }
+    return BatchItemResult{Analysis: analysis}
+}
+
+func getEnvInt(key string, defaultValue int) int {
+    raw := getEnv(key, "")
+    if raw == "" {
+        return defaultValue
+    }
+    n, err := strconv.Atoi(raw)
+    if err != nil {
+        return defaultValue
+    }
+    return n
+}
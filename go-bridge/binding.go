@@ -0,0 +1,144 @@
+package main
+
+import (
+    "encoding/json"
+    "encoding/xml"
+    "fmt"
+    "io"
+    "mime"
+    "net/http"
+
+    "github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// bindMessage decodes a Message from the request body according to its
+// Content-Type (Echo's default binder uses the same dispatch), or from
+// query parameters for GET debug calls. It rejects empty bodies and runs
+// struct-tag validation before returning.
+func bindMessage(r *http.Request, out *Message) error {
+    if r.Method == http.MethodGet {
+        bindQuery(r, out)
+        return validate.Struct(out)
+    }
+
+    if r.ContentLength == 0 {
+        return errEmptyBody
+    }
+
+    mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+    if err != nil {
+        mediaType = "application/json"
+    }
+
+    switch mediaType {
+    case "", "application/json":
+        err = bindJSON(r, out)
+    case "application/xml", "text/xml":
+        err = bindXML(r, out)
+    case "application/x-www-form-urlencoded":
+        err = bindForm(r, out)
+    case "multipart/form-data":
+        err = bindMultipart(r, out)
+    default:
+        err = fmt.Errorf("unsupported content type %q", mediaType)
+    }
+    if err != nil {
+        return err
+    }
+
+    return validate.Struct(out)
+}
+
+var errEmptyBody = fmt.Errorf("request body is empty")
+
+func bindJSON(r *http.Request, out *Message) error {
+    dec := json.NewDecoder(r.Body)
+    if err := dec.Decode(out); err != nil {
+        if err == io.EOF {
+            return errEmptyBody
+        }
+        return err
+    }
+    return nil
+}
+
+func bindXML(r *http.Request, out *Message) error {
+    dec := xml.NewDecoder(r.Body)
+    if err := dec.Decode(out); err != nil {
+        if err == io.EOF {
+            return errEmptyBody
+        }
+        return err
+    }
+    return nil
+}
+
+func bindForm(r *http.Request, out *Message) error {
+    if err := r.ParseForm(); err != nil {
+        return err
+    }
+    out.Content = r.PostForm.Get("content")
+    out.Context = r.PostForm.Get("context")
+    out.Backend = r.PostForm.Get("backend")
+    return nil
+}
+
+func bindMultipart(r *http.Request, out *Message) error {
+    // 32MB matches http.Request.ParseMultipartForm's own historical default.
+    if err := r.ParseMultipartForm(32 << 20); err != nil {
+        return err
+    }
+    out.Content = r.FormValue("content")
+    out.Backend = r.FormValue("backend")
+
+    if file, _, err := r.FormFile("context"); err == nil {
+        defer file.Close()
+        data, err := io.ReadAll(file)
+        if err != nil {
+            return err
+        }
+        out.Context = string(data)
+    } else {
+        out.Context = r.FormValue("context")
+    }
+    return nil
+}
+
+func bindQuery(r *http.Request, out *Message) {
+    q := r.URL.Query()
+    out.Content = q.Get("content")
+    out.Context = q.Get("context")
+    out.Backend = q.Get("backend")
+}
+
+// fieldErrorsResponse turns validator.ValidationErrors into a structured,
+// field-level response body instead of a single opaque error string.
+func fieldErrorsResponse(err error) map[string]interface{} {
+    var verrs validator.ValidationErrors
+    if ok := asValidationErrors(err, &verrs); !ok {
+        return map[string]interface{}{"error": err.Error()}
+    }
+
+    fields := make([]map[string]string, 0, len(verrs))
+    for _, fe := range verrs {
+        fields = append(fields, map[string]string{
+            "field": fe.Field(),
+            "rule":  fe.Tag(),
+        })
+    }
+    return map[string]interface{}{
+        "error":  "validation failed",
+        "fields": fields,
+    }
+}
+
+func asValidationErrors(err error, target *validator.ValidationErrors) bool {
+    verrs, ok := err.(validator.ValidationErrors)
+    if !ok {
+        return false
+    }
+    *target = verrs
+    return true
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+    "mime/multipart"
+    "net/http"
+    "net/http/httptest"
+    "net/url"
+    "strings"
+    "testing"
+)
+
+func TestBindMessageContentTypeDispatch(t *testing.T) {
+    tests := []struct {
+        name        string
+        method      string
+        contentType string
+        body        func() string
+        wantErr     bool
+        wantContent string
+        wantBackend string
+    }{
+        {
+            name:        "json",
+            method:      http.MethodPost,
+            contentType: "application/json",
+            body:        func() string { return `{"content":"hi","backend":"noop"}` },
+            wantContent: "hi",
+            wantBackend: "noop",
+        },
+        {
+            name:        "json no content type defaults to json",
+            method:      http.MethodPost,
+            contentType: "",
+            body:        func() string { return `{"content":"hi"}` },
+            wantContent: "hi",
+        },
+        {
+            name:        "xml",
+            method:      http.MethodPost,
+            contentType: "application/xml",
+            body:        func() string { return `<message><content>hi</content></message>` },
+            wantContent: "hi",
+        },
+        {
+            name:        "form",
+            method:      http.MethodPost,
+            contentType: "application/x-www-form-urlencoded",
+            body:        func() string { return url.Values{"content": {"hi"}}.Encode() },
+            wantContent: "hi",
+        },
+        {
+            name:        "empty body rejected",
+            method:      http.MethodPost,
+            contentType: "application/json",
+            body:        func() string { return "" },
+            wantErr:     true,
+        },
+        {
+            name:        "unsupported content type rejected",
+            method:      http.MethodPost,
+            contentType: "application/octet-stream",
+            body:        func() string { return "hi" },
+            wantErr:     true,
+        },
+        {
+            name:        "missing required content fails validation",
+            method:      http.MethodPost,
+            contentType: "application/json",
+            body:        func() string { return `{"context":"only context"}` },
+            wantErr:     true,
+        },
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            body := tt.body()
+            req := httptest.NewRequest(tt.method, "/process", strings.NewReader(body))
+            if tt.contentType != "" {
+                req.Header.Set("Content-Type", tt.contentType)
+            }
+            req.ContentLength = int64(len(body))
+
+            var msg Message
+            err := bindMessage(req, &msg)
+
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("bindMessage() = nil, want error")
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("bindMessage() error = %v, want nil", err)
+            }
+            if msg.Content != tt.wantContent {
+                t.Errorf("Content = %q, want %q", msg.Content, tt.wantContent)
+            }
+            if tt.wantBackend != "" && msg.Backend != tt.wantBackend {
+                t.Errorf("Backend = %q, want %q", msg.Backend, tt.wantBackend)
+            }
+        })
+    }
+}
+
+func TestBindMessageMultipart(t *testing.T) {
+    var buf strings.Builder
+    mw := multipart.NewWriter(&buf)
+    mw.WriteField("content", "hi")
+    mw.WriteField("context", "ctx")
+    mw.Close()
+
+    req := httptest.NewRequest(http.MethodPost, "/process", strings.NewReader(buf.String()))
+    req.Header.Set("Content-Type", mw.FormDataContentType())
+    req.ContentLength = int64(buf.Len())
+
+    var msg Message
+    if err := bindMessage(req, &msg); err != nil {
+        t.Fatalf("bindMessage() error = %v", err)
+    }
+    if msg.Content != "hi" || msg.Context != "ctx" {
+        t.Errorf("got Content=%q Context=%q, want Content=hi Context=ctx", msg.Content, msg.Context)
+    }
+}
+
+func TestBindMessageQueryParamsOnGet(t *testing.T) {
+    req := httptest.NewRequest(http.MethodGet, "/process?content=hi&backend=noop", nil)
+
+    var msg Message
+    if err := bindMessage(req, &msg); err != nil {
+        t.Fatalf("bindMessage() error = %v", err)
+    }
+    if msg.Content != "hi" || msg.Backend != "noop" {
+        t.Errorf("got Content=%q Backend=%q, want Content=hi Backend=noop", msg.Content, msg.Backend)
+    }
+}
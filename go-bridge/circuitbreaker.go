@@ -0,0 +1,81 @@
+package main
+
+import (
+    "sync"
+    "time"
+)
+
+type breakerState int
+
+const (
+    breakerClosed breakerState = iota
+    breakerOpen
+    breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+    switch s {
+    case breakerOpen:
+        return "open"
+    case breakerHalfOpen:
+        return "half-open"
+    default:
+        return "closed"
+    }
+}
+
+// CircuitBreaker opens after a run of consecutive failures and rejects
+// calls for a cooldown period, giving a struggling CROD instance time to
+// recover instead of being hammered with retries.
+type CircuitBreaker struct {
+    failureThreshold int
+    cooldown         time.Duration
+
+    mu          sync.Mutex
+    state       breakerState
+    failures    int
+    openedAt    time.Time
+}
+
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+    return &CircuitBreaker{failureThreshold: failureThreshold, cooldown: cooldown}
+}
+
+// Allow reports whether a call should proceed. It transitions an open
+// breaker to half-open once the cooldown has elapsed.
+func (b *CircuitBreaker) Allow() bool {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    if b.state == breakerOpen {
+        if time.Since(b.openedAt) >= b.cooldown {
+            b.state = breakerHalfOpen
+            return true
+        }
+        return false
+    }
+    return true
+}
+
+func (b *CircuitBreaker) RecordSuccess() {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.failures = 0
+    b.state = breakerClosed
+}
+
+func (b *CircuitBreaker) RecordFailure() {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.failures++
+    if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+        b.state = breakerOpen
+        b.openedAt = time.Now()
+    }
+}
+
+func (b *CircuitBreaker) State() string {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.state.String()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "testing"
+    "time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+    b := NewCircuitBreaker(3, time.Minute)
+
+    for i := 0; i < 2; i++ {
+        if !b.Allow() {
+            t.Fatalf("call %d: expected breaker to allow before threshold", i)
+        }
+        b.RecordFailure()
+    }
+    if got := b.State(); got != "closed" {
+        t.Fatalf("state = %q, want closed before threshold reached", got)
+    }
+
+    b.RecordFailure()
+    if got := b.State(); got != "open" {
+        t.Fatalf("state = %q, want open after %d consecutive failures", got, 3)
+    }
+    if b.Allow() {
+        t.Fatal("expected breaker to reject calls while open")
+    }
+}
+
+func TestCircuitBreakerHalfOpenAfterCooldown(t *testing.T) {
+    b := NewCircuitBreaker(1, 10*time.Millisecond)
+
+    b.RecordFailure()
+    if got := b.State(); got != "open" {
+        t.Fatalf("state = %q, want open", got)
+    }
+    if b.Allow() {
+        t.Fatal("expected breaker to reject calls immediately after opening")
+    }
+
+    time.Sleep(20 * time.Millisecond)
+
+    if !b.Allow() {
+        t.Fatal("expected breaker to allow a trial call after cooldown")
+    }
+    if got := b.State(); got != "half-open" {
+        t.Fatalf("state = %q, want half-open after cooldown", got)
+    }
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+    b := NewCircuitBreaker(1, 10*time.Millisecond)
+
+    b.RecordFailure()
+    time.Sleep(20 * time.Millisecond)
+    b.Allow() // transitions to half-open
+
+    b.RecordFailure()
+    if got := b.State(); got != "open" {
+        t.Fatalf("state = %q, want open after a half-open trial fails", got)
+    }
+}
+
+func TestCircuitBreakerSuccessCloses(t *testing.T) {
+    b := NewCircuitBreaker(1, 10*time.Millisecond)
+
+    b.RecordFailure()
+    time.Sleep(20 * time.Millisecond)
+    b.Allow() // transitions to half-open
+
+    b.RecordSuccess()
+    if got := b.State(); got != "closed" {
+        t.Fatalf("state = %q, want closed after a successful trial", got)
+    }
+}
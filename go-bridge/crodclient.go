@@ -0,0 +1,110 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "net"
+    "net/http"
+    "time"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/propagation"
+)
+
+// crodHTTPClient is shared across requests so idle connections to CROD are
+// reused instead of handshaking on every analysis.
+var crodHTTPClient = &http.Client{
+    Timeout: 10 * time.Second,
+    Transport: &http.Transport{
+        MaxIdleConnsPerHost: 16,
+        IdleConnTimeout:     90 * time.Second,
+    },
+}
+
+var crodBreaker = NewCircuitBreaker(5, 30*time.Second)
+
+var errCircuitOpen = errors.New("crod circuit breaker open, short-circuiting request")
+
+func isCircuitBreakerError(err error) bool { return errors.Is(err, errCircuitOpen) }
+
+func isTimeoutError(err error) bool { return errors.Is(err, context.DeadlineExceeded) }
+
+// Retry tuning for postToCROD. These are vars rather than consts so tests
+// can shrink them for fast, deterministic timing assertions.
+var (
+    retryInitialDelay = 100 * time.Millisecond
+    retryMaxDelay     = 5 * time.Second
+    retryMaxElapsed   = 30 * time.Second
+)
+
+// postToCROD sends the analysis payload to CROD, retrying with exponential
+// backoff on 5xx responses and network errors, and short-circuiting
+// immediately if the circuit breaker is open. 4xx responses are not
+// retried, since retrying a bad request can't help; they come back as an
+// error carrying CROD's status code and response body.
+func postToCROD(ctx context.Context, jsonData []byte) (*http.Response, error) {
+    if !crodBreaker.Allow() {
+        return nil, errCircuitOpen
+    }
+
+    deadline := time.Now().Add(retryMaxElapsed)
+    delay := retryInitialDelay
+
+    for attempt := 0; ; attempt++ {
+        req, err := http.NewRequestWithContext(ctx, http.MethodPost, crodURL, bytes.NewReader(jsonData))
+        if err != nil {
+            return nil, err
+        }
+        req.Header.Set("Content-Type", "application/json")
+        otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+        resp, err := crodHTTPClient.Do(req)
+        if err == nil && resp.StatusCode < 400 {
+            crodBreaker.RecordSuccess()
+            return resp, nil
+        }
+
+        if err == nil && resp.StatusCode < 500 {
+            // CROD responded, so it's healthy; retrying a bad request
+            // can't help, so fail fast instead of burning the retry budget.
+            crodBreaker.RecordSuccess()
+            body, _ := io.ReadAll(resp.Body)
+            resp.Body.Close()
+            return nil, fmt.Errorf("crod returned %d: %s", resp.StatusCode, string(body))
+        }
+
+        if err != nil {
+            var netErr net.Error
+            if ctx.Err() != nil || !errors.As(err, &netErr) {
+                // Not a retryable network error (e.g. a canceled context).
+                crodBreaker.RecordFailure()
+                return nil, err
+            }
+        } else {
+            resp.Body.Close()
+        }
+
+        crodBreaker.RecordFailure()
+
+        if time.Now().Add(delay).After(deadline) {
+            if err != nil {
+                return nil, fmt.Errorf("crod request failed after retries: %w", err)
+            }
+            return nil, fmt.Errorf("crod request failed after retries: status %d", resp.StatusCode)
+        }
+
+        select {
+        case <-time.After(delay):
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        }
+
+        delay *= 2
+        if delay > retryMaxDelay {
+            delay = retryMaxDelay
+        }
+    }
+}
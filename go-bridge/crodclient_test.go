@@ -0,0 +1,133 @@
+package main
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// withFastRetries shrinks the retry timing knobs and gives postToCROD a
+// fresh circuit breaker for the duration of a test, restoring the package
+// defaults afterwards so tests don't bleed state into each other.
+func withFastRetries(t *testing.T, maxElapsed time.Duration) {
+    t.Helper()
+    origInitial, origMax, origElapsed, origBreaker := retryInitialDelay, retryMaxDelay, retryMaxElapsed, crodBreaker
+    retryInitialDelay = 5 * time.Millisecond
+    retryMaxDelay = 20 * time.Millisecond
+    retryMaxElapsed = maxElapsed
+    crodBreaker = NewCircuitBreaker(1000, time.Minute)
+    t.Cleanup(func() {
+        retryInitialDelay, retryMaxDelay, retryMaxElapsed, crodBreaker = origInitial, origMax, origElapsed, origBreaker
+    })
+}
+
+func withCrodURL(t *testing.T, url string) {
+    t.Helper()
+    orig := crodURL
+    crodURL = url
+    t.Cleanup(func() { crodURL = orig })
+}
+
+func TestPostToCRODRetriesOn5xx(t *testing.T) {
+    withFastRetries(t, time.Second)
+
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if atomic.AddInt32(&calls, 1) < 3 {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        w.WriteHeader(http.StatusOK)
+    }))
+    defer srv.Close()
+    withCrodURL(t, srv.URL)
+
+    resp, err := postToCROD(context.Background(), []byte(`{}`))
+    if err != nil {
+        t.Fatalf("postToCROD() error = %v, want nil after eventual success", err)
+    }
+    resp.Body.Close()
+    if got := atomic.LoadInt32(&calls); got != 3 {
+        t.Errorf("calls = %d, want 3 (2 failures then a success)", got)
+    }
+}
+
+func TestPostToCRODNoRetryOn4xx(t *testing.T) {
+    withFastRetries(t, time.Second)
+
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&calls, 1)
+        w.WriteHeader(http.StatusBadRequest)
+    }))
+    defer srv.Close()
+    withCrodURL(t, srv.URL)
+
+    _, err := postToCROD(context.Background(), []byte(`{}`))
+    if err == nil {
+        t.Fatal("postToCROD() error = nil, want error for a 4xx response")
+    }
+    if got := atomic.LoadInt32(&calls); got != 1 {
+        t.Errorf("calls = %d, want 1 (no retry on 4xx)", got)
+    }
+}
+
+func TestPostToCRODRespectsMaxElapsed(t *testing.T) {
+    withFastRetries(t, 50*time.Millisecond)
+
+    var calls int32
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        atomic.AddInt32(&calls, 1)
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer srv.Close()
+    withCrodURL(t, srv.URL)
+
+    start := time.Now()
+    _, err := postToCROD(context.Background(), []byte(`{}`))
+    elapsed := time.Since(start)
+
+    if err == nil {
+        t.Fatal("postToCROD() error = nil, want error once the retry budget is exhausted")
+    }
+    if elapsed > 500*time.Millisecond {
+        t.Errorf("elapsed = %s, want roughly bounded by retryMaxElapsed (50ms)", elapsed)
+    }
+    if got := atomic.LoadInt32(&calls); got < 2 {
+        t.Errorf("calls = %d, want at least 2 (at least one retry before giving up)", got)
+    }
+}
+
+func TestPostToCRODBackoffDelayGrows(t *testing.T) {
+    withFastRetries(t, time.Second)
+
+    var mu sync.Mutex
+    var gaps []time.Duration
+    last := time.Now()
+    srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        now := time.Now()
+        gaps = append(gaps, now.Sub(last))
+        last = now
+        mu.Unlock()
+        w.WriteHeader(http.StatusServiceUnavailable)
+    }))
+    defer srv.Close()
+    withCrodURL(t, srv.URL)
+
+    postToCROD(context.Background(), []byte(`{}`))
+
+    mu.Lock()
+    defer mu.Unlock()
+    if len(gaps) < 3 {
+        t.Fatalf("got %d requests, want at least 3 to observe backoff growth", len(gaps))
+    }
+    // gaps[0] is the time to the first request, not a backoff delay.
+    if gaps[2] < gaps[1] {
+        t.Errorf("second backoff gap %s was not >= first gap %s, want exponential growth", gaps[2], gaps[1])
+    }
+}
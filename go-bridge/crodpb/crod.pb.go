@@ -0,0 +1,21 @@
+// Package crodpb defines the messages exchanged with CROD's gRPC service
+// (see crod.proto). There's no protoc toolchain wired into this build, so
+// these are plain hand-written structs rather than protoc-gen-go output;
+// crod_grpc.pb.go pairs them with a JSON codec instead of requiring them
+// to implement proto.Message.
+package crodpb
+
+type AnalyzeRequest struct {
+    Message string `json:"message"`
+    Context string `json:"context"`
+}
+
+type AnalyzeResponse struct {
+    Confidence       float64  `json:"confidence"`
+    Mood             string   `json:"mood"`
+    Intent           string   `json:"intent"`
+    NeuronsActivated int32    `json:"neurons_activated"`
+    Tone             string   `json:"tone"`
+    FocusOn          []string `json:"focus_on"`
+    Avoid            []string `json:"avoid"`
+}
@@ -0,0 +1,46 @@
+// crod_grpc.pb.go hand-implements the client side of the CrodService RPC
+// described in crod.proto. It registers a JSON codec under the
+// "json" content-subtype so AnalyzeRequest/AnalyzeResponse can travel over
+// gRPC without implementing proto.Message.
+package crodpb
+
+import (
+    "context"
+    "encoding/json"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/encoding"
+)
+
+const jsonCodecName = "json"
+
+func init() {
+    encoding.RegisterCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                               { return jsonCodecName }
+
+type CrodServiceClient interface {
+    Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error)
+}
+
+type crodServiceClient struct {
+    cc grpc.ClientConnInterface
+}
+
+func NewCrodServiceClient(cc grpc.ClientConnInterface) CrodServiceClient {
+    return &crodServiceClient{cc}
+}
+
+func (c *crodServiceClient) Analyze(ctx context.Context, in *AnalyzeRequest, opts ...grpc.CallOption) (*AnalyzeResponse, error) {
+    out := new(AnalyzeResponse)
+    opts = append(opts, grpc.CallContentSubtype(jsonCodecName))
+    if err := c.cc.Invoke(ctx, "/crodpb.CrodService/Analyze", in, out, opts...); err != nil {
+        return nil, err
+    }
+    return out, nil
+}
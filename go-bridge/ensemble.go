@@ -0,0 +1,122 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "sync"
+)
+
+// EnsembleAnalyzer runs several analyzers concurrently and merges their
+// results via confidence-weighted voting: the mood/intent with the highest
+// summed confidence across backends wins, and the reported confidence is
+// the average across all backends that answered.
+type EnsembleAnalyzer struct {
+    name     string
+    backends []Analyzer
+}
+
+func NewEnsembleAnalyzer(name string, backends ...Analyzer) *EnsembleAnalyzer {
+    return &EnsembleAnalyzer{name: name, backends: backends}
+}
+
+func (e *EnsembleAnalyzer) Name() string { return e.name }
+
+func (e *EnsembleAnalyzer) Analyze(ctx context.Context, msg Message) (*CrodAnalysis, error) {
+    if len(e.backends) == 0 {
+        return nil, fmt.Errorf("ensemble %q has no backends", e.name)
+    }
+
+    results := make([]*CrodAnalysis, len(e.backends))
+    errs := make([]error, len(e.backends))
+
+    var wg sync.WaitGroup
+    for i, backend := range e.backends {
+        wg.Add(1)
+        go func(i int, backend Analyzer) {
+            defer wg.Done()
+            results[i], errs[i] = backend.Analyze(ctx, msg)
+        }(i, backend)
+    }
+    wg.Wait()
+
+    moodVotes := newVoteTally()
+    intentVotes := newVoteTally()
+    var confidenceSum float64
+    var ok int
+
+    for i, r := range results {
+        if errs[i] != nil || r == nil {
+            continue
+        }
+        ok++
+        confidenceSum += r.CrodAnalysis.Confidence
+        moodVotes.add(r.CrodAnalysis.Mood, r.CrodAnalysis.Confidence)
+        intentVotes.add(r.CrodAnalysis.Intent, r.CrodAnalysis.Confidence)
+    }
+
+    if ok == 0 {
+        return nil, fmt.Errorf("ensemble %q: all backends failed: %v", e.name, errs)
+    }
+
+    var merged CrodAnalysis
+    merged.OriginalMessage = msg.Content
+    merged.CrodAnalysis.Confidence = confidenceSum / float64(ok)
+    merged.CrodAnalysis.Mood = topVote(moodVotes)
+    merged.CrodAnalysis.Intent = topVote(intentVotes)
+    for _, r := range results {
+        if r != nil {
+            merged.CrodAnalysis.NeuronsActivated += r.CrodAnalysis.NeuronsActivated
+        }
+    }
+
+    return &merged, nil
+}
+
+// registerEnsemble wires up the "ensemble" backend from the analyzers that
+// are already registered. It runs from main() rather than an init() func so
+// it doesn't depend on file-level init ordering of the other backends.
+func registerEnsemble(names ...string) {
+    var backends []Analyzer
+    for _, name := range names {
+        if a, ok := analyzerRegistry[name]; ok {
+            backends = append(backends, a)
+        }
+    }
+    if len(backends) == 0 {
+        return
+    }
+    RegisterAnalyzer(NewEnsembleAnalyzer("ensemble", backends...))
+}
+
+// voteTally accumulates confidence scores per candidate while remembering
+// the order candidates were first seen, so topVote can break ties
+// deterministically instead of relying on map iteration order.
+type voteTally struct {
+    scores map[string]float64
+    order  []string
+}
+
+func newVoteTally() *voteTally {
+    return &voteTally{scores: map[string]float64{}}
+}
+
+func (t *voteTally) add(candidate string, confidence float64) {
+    if _, seen := t.scores[candidate]; !seen {
+        t.order = append(t.order, candidate)
+    }
+    t.scores[candidate] += confidence
+}
+
+// topVote returns the candidate with the highest summed confidence,
+// breaking ties in favor of whichever candidate was first seen (i.e. the
+// earliest backend to vote for it) rather than Go's randomized map order.
+func topVote(t *voteTally) string {
+    var best string
+    var bestScore float64
+    for _, k := range t.order {
+        if v := t.scores[k]; best == "" || v > bestScore {
+            best, bestScore = k, v
+        }
+    }
+    return best
+}
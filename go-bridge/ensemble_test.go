@@ -0,0 +1,110 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "testing"
+)
+
+// fakeAnalyzer returns a canned analysis or error, for exercising
+// EnsembleAnalyzer without a real CROD backend.
+type fakeAnalyzer struct {
+    name       string
+    mood       string
+    intent     string
+    confidence float64
+    neurons    int
+    err        error
+}
+
+func (f fakeAnalyzer) Name() string { return f.name }
+
+func (f fakeAnalyzer) Analyze(_ context.Context, _ Message) (*CrodAnalysis, error) {
+    if f.err != nil {
+        return nil, f.err
+    }
+    var a CrodAnalysis
+    a.CrodAnalysis.Mood = f.mood
+    a.CrodAnalysis.Intent = f.intent
+    a.CrodAnalysis.Confidence = f.confidence
+    a.CrodAnalysis.NeuronsActivated = f.neurons
+    return &a, nil
+}
+
+func TestEnsembleAnalyzerConfidenceWeightedVoting(t *testing.T) {
+    e := NewEnsembleAnalyzer("ensemble",
+        fakeAnalyzer{name: "a", mood: "positive", intent: "greet", confidence: 0.9, neurons: 10},
+        fakeAnalyzer{name: "b", mood: "negative", intent: "greet", confidence: 0.2, neurons: 5},
+    )
+
+    got, err := e.Analyze(context.Background(), Message{Content: "hi"})
+    if err != nil {
+        t.Fatalf("Analyze() error = %v", err)
+    }
+    if got.CrodAnalysis.Mood != "positive" {
+        t.Errorf("Mood = %q, want %q (higher confidence vote)", got.CrodAnalysis.Mood, "positive")
+    }
+    if got.CrodAnalysis.Intent != "greet" {
+        t.Errorf("Intent = %q, want %q (only candidate)", got.CrodAnalysis.Intent, "greet")
+    }
+    wantConfidence := (0.9 + 0.2) / 2
+    if got.CrodAnalysis.Confidence != wantConfidence {
+        t.Errorf("Confidence = %v, want %v", got.CrodAnalysis.Confidence, wantConfidence)
+    }
+    if got.CrodAnalysis.NeuronsActivated != 15 {
+        t.Errorf("NeuronsActivated = %d, want 15", got.CrodAnalysis.NeuronsActivated)
+    }
+}
+
+func TestEnsembleAnalyzerToleratesPartialFailure(t *testing.T) {
+    e := NewEnsembleAnalyzer("ensemble",
+        fakeAnalyzer{name: "a", mood: "neutral", intent: "ask", confidence: 0.5, neurons: 3},
+        fakeAnalyzer{name: "b", err: fmt.Errorf("backend b unavailable")},
+    )
+
+    got, err := e.Analyze(context.Background(), Message{Content: "hi"})
+    if err != nil {
+        t.Fatalf("Analyze() error = %v, want nil since one backend succeeded", err)
+    }
+    if got.CrodAnalysis.Mood != "neutral" {
+        t.Errorf("Mood = %q, want %q", got.CrodAnalysis.Mood, "neutral")
+    }
+}
+
+func TestEnsembleAnalyzerAllBackendsFail(t *testing.T) {
+    e := NewEnsembleAnalyzer("ensemble",
+        fakeAnalyzer{name: "a", err: fmt.Errorf("down")},
+        fakeAnalyzer{name: "b", err: fmt.Errorf("down")},
+    )
+
+    if _, err := e.Analyze(context.Background(), Message{Content: "hi"}); err == nil {
+        t.Fatal("Analyze() = nil error, want error when every backend fails")
+    }
+}
+
+func TestEnsembleAnalyzerTiedVotesAreDeterministic(t *testing.T) {
+    e := NewEnsembleAnalyzer("ensemble",
+        fakeAnalyzer{name: "a", mood: "positive", intent: "greet", confidence: 0.5},
+        fakeAnalyzer{name: "b", mood: "negative", intent: "ask", confidence: 0.5},
+    )
+
+    for i := 0; i < 50; i++ {
+        got, err := e.Analyze(context.Background(), Message{Content: "hi"})
+        if err != nil {
+            t.Fatalf("Analyze() error = %v", err)
+        }
+        if got.CrodAnalysis.Mood != "positive" {
+            t.Fatalf("run %d: Mood = %q, want %q (tie broken by first-seen backend)", i, got.CrodAnalysis.Mood, "positive")
+        }
+        if got.CrodAnalysis.Intent != "greet" {
+            t.Fatalf("run %d: Intent = %q, want %q (tie broken by first-seen backend)", i, got.CrodAnalysis.Intent, "greet")
+        }
+    }
+}
+
+func TestEnsembleAnalyzerNoBackends(t *testing.T) {
+    e := NewEnsembleAnalyzer("ensemble")
+    if _, err := e.Analyze(context.Background(), Message{Content: "hi"}); err == nil {
+        t.Fatal("Analyze() = nil error, want error with no backends configured")
+    }
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+    "container/list"
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/gomodule/redigo/redis"
+)
+
+type JobStatus string
+
+const (
+    JobPending JobStatus = "pending"
+    JobRunning JobStatus = "running"
+    JobDone    JobStatus = "done"
+    JobFailed  JobStatus = "failed"
+)
+
+type Job struct {
+    ID     string        `json:"id"`
+    Status JobStatus     `json:"status"`
+    Result *CrodAnalysis `json:"result,omitempty"`
+    Error  string        `json:"error,omitempty"`
+}
+
+const jobCacheSize = 1024
+
+// jobLRU is an in-memory cache of recent jobs, evicting the
+// least-recently-used entry once it grows past its capacity.
+type jobLRU struct {
+    mu    sync.Mutex
+    cap   int
+    ll    *list.List
+    items map[string]*list.Element
+}
+
+type jobEntry struct {
+    key string
+    job Job
+}
+
+func newJobLRU(capacity int) *jobLRU {
+    return &jobLRU{cap: capacity, ll: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (c *jobLRU) Set(job Job) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if el, ok := c.items[job.ID]; ok {
+        el.Value.(*jobEntry).job = job
+        c.ll.MoveToFront(el)
+        return
+    }
+    el := c.ll.PushFront(&jobEntry{key: job.ID, job: job})
+    c.items[job.ID] = el
+    if c.ll.Len() > c.cap {
+        oldest := c.ll.Back()
+        if oldest != nil {
+            c.ll.Remove(oldest)
+            delete(c.items, oldest.Value.(*jobEntry).key)
+        }
+    }
+}
+
+func (c *jobLRU) Get(id string) (Job, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    el, ok := c.items[id]
+    if !ok {
+        return Job{}, false
+    }
+    c.ll.MoveToFront(el)
+    return el.Value.(*jobEntry).job, true
+}
+
+var jobCache = newJobLRU(jobCacheSize)
+
+// redisPool persists jobs beyond this process's memory when JOBS_REDIS_ADDR
+// is configured; it stays nil (and saveJob/loadJob fall back to jobCache
+// alone) otherwise.
+var redisPool *redis.Pool
+
+func initJobStore() {
+    jobsCtx, cancelJobsCtx = context.WithCancel(context.Background())
+
+    addr := getEnv("JOBS_REDIS_ADDR", "")
+    if addr == "" {
+        return
+    }
+    redisPool = &redis.Pool{
+        MaxIdle:     8,
+        IdleTimeout: 240 * time.Second,
+        Dial:        func() (redis.Conn, error) { return redis.Dial("tcp", addr) },
+    }
+}
+
+func saveJob(job Job) {
+    jobCache.Set(job)
+    if redisPool == nil {
+        return
+    }
+    conn := redisPool.Get()
+    defer conn.Close()
+    data, err := json.Marshal(job)
+    if err != nil {
+        log.Printf("❌ failed to marshal job %s: %v", job.ID, err)
+        return
+    }
+    if _, err := conn.Do("SET", "job:"+job.ID, data, "EX", 3600); err != nil {
+        log.Printf("❌ failed to persist job %s to redis: %v", job.ID, err)
+    }
+}
+
+func loadJob(id string) (Job, bool) {
+    if job, ok := jobCache.Get(id); ok {
+        return job, true
+    }
+    if redisPool == nil {
+        return Job{}, false
+    }
+    conn := redisPool.Get()
+    defer conn.Close()
+    data, err := redis.Bytes(conn.Do("GET", "job:"+id))
+    if err != nil {
+        return Job{}, false
+    }
+    var job Job
+    if err := json.Unmarshal(data, &job); err != nil {
+        return Job{}, false
+    }
+    return job, true
+}
+
+func newJobID() string {
+    b := make([]byte, 16)
+    rand.Read(b)
+    return hex.EncodeToString(b)
+}
+
+// inFlightJobs tracks background job goroutines so shutdown can drain them
+// before the process exits.
+var inFlightJobs sync.WaitGroup
+
+// jobsCtx is the parent context for all background jobs. cancelJobsCtx is
+// called once shutdown has waited shutdownTimeout for jobs to finish on
+// their own, so jobs that are still running get cut off instead of
+// leaking past process exit.
+var (
+    jobsCtx       context.Context
+    cancelJobsCtx context.CancelFunc
+)
+
+// handleJobsCreate starts an asynchronous analysis and returns its job id
+// immediately; the result is fetched later via GET /jobs/{id}.
+func handleJobsCreate(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodPost {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    var msg Message
+    if err := bindMessage(r, &msg); err != nil {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(fieldErrorsResponse(err))
+        return
+    }
+
+    job := Job{ID: newJobID(), Status: JobPending}
+    saveJob(job)
+
+    inFlightJobs.Add(1)
+    go runJob(job, msg)
+
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusAccepted)
+    json.NewEncoder(w).Encode(job)
+}
+
+// runJob executes a job's analysis in the background, on jobsCtx rather
+// than the originating request's — the job is meant to outlive the HTTP
+// call that created it, but it's still cut off at shutdownTimeout so a
+// slow job can't block shutdown forever.
+func runJob(job Job, msg Message) {
+    defer inFlightJobs.Done()
+
+    job.Status = JobRunning
+    saveJob(job)
+
+    backendName := msg.Backend
+    if backendName == "" {
+        backendName = defaultBackend
+    }
+
+    analyzer, err := getAnalyzer(backendName)
+    if err == nil {
+        ctx, cancel := context.WithTimeout(jobsCtx, shutdownTimeout)
+        defer cancel()
+        job.Result, err = analyzer.Analyze(ctx, msg)
+    }
+
+    if err != nil {
+        job.Status = JobFailed
+        job.Error = err.Error()
+    } else {
+        job.Status = JobDone
+    }
+    saveJob(job)
+}
+
+// handleJobsGet returns a job's current status and, once done, its result.
+func handleJobsGet(w http.ResponseWriter, r *http.Request) {
+    if r.Method != http.MethodGet {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
+    }
+
+    id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+    if id == "" {
+        http.Error(w, "missing job id", http.StatusBadRequest)
+        return
+    }
+
+    job, ok := loadJob(id)
+    if !ok {
+        http.Error(w, fmt.Sprintf("job %q not found", id), http.StatusNotFound)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/json")
+    json.NewEncoder(w).Encode(job)
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestJobLRUGetSet(t *testing.T) {
+    c := newJobLRU(2)
+    c.Set(Job{ID: "a", Status: JobDone})
+
+    got, ok := c.Get("a")
+    if !ok {
+        t.Fatal("Get(a) = not found, want found")
+    }
+    if got.Status != JobDone {
+        t.Errorf("Status = %q, want %q", got.Status, JobDone)
+    }
+
+    if _, ok := c.Get("missing"); ok {
+        t.Error("Get(missing) = found, want not found")
+    }
+}
+
+func TestJobLRUEvictsLeastRecentlyUsed(t *testing.T) {
+    c := newJobLRU(2)
+    c.Set(Job{ID: "a"})
+    c.Set(Job{ID: "b"})
+    c.Set(Job{ID: "c"}) // over capacity: "a" was least recently touched, evicted
+
+    if _, ok := c.Get("a"); ok {
+        t.Error("Get(a) = found, want evicted")
+    }
+    if _, ok := c.Get("b"); !ok {
+        t.Error("Get(b) = not found, want present")
+    }
+    if _, ok := c.Get("c"); !ok {
+        t.Error("Get(c) = not found, want present")
+    }
+}
+
+func TestJobLRUGetRefreshesRecency(t *testing.T) {
+    c := newJobLRU(2)
+    c.Set(Job{ID: "a"})
+    c.Set(Job{ID: "b"})
+
+    c.Get("a") // touch "a" so "b" becomes least recently used
+    c.Set(Job{ID: "c"})
+
+    if _, ok := c.Get("b"); ok {
+        t.Error("Get(b) = found, want evicted after being passed over")
+    }
+    if _, ok := c.Get("a"); !ok {
+        t.Error("Get(a) = not found, want present (recently touched)")
+    }
+}
+
+func TestJobLRUSetUpdatesExistingEntry(t *testing.T) {
+    c := newJobLRU(2)
+    c.Set(Job{ID: "a", Status: JobPending})
+    c.Set(Job{ID: "a", Status: JobDone})
+
+    got, ok := c.Get("a")
+    if !ok {
+        t.Fatal("Get(a) = not found, want found")
+    }
+    if got.Status != JobDone {
+        t.Errorf("Status = %q, want %q (updated in place)", got.Status, JobDone)
+    }
+    if c.ll.Len() != 1 {
+        t.Errorf("cache length = %d, want 1 (update should not grow the list)", c.ll.Len())
+    }
+}
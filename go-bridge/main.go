@@ -1,18 +1,29 @@
 package main
 
 import (
-    "bytes"
+    "context"
     "encoding/json"
+    "encoding/xml"
     "io"
     "log"
     "net/http"
+    "net/url"
     "os"
+    "os/signal"
+    "strings"
+    "syscall"
     "time"
+
+    "github.com/gorilla/websocket"
 )
 
 type Message struct {
-    Content string `json:"content"`
-    Context string `json:"context"`
+    XMLName xml.Name `json:"-" xml:"message"`
+    Content string   `json:"content" xml:"content" validate:"required,max=32768"`
+    Context string   `json:"context" xml:"context"`
+    // Backend selects which registered Analyzer handles this request
+    // (e.g. "http", "noop", "grpc", "ensemble"). Empty uses defaultBackend.
+    Backend string `json:"backend,omitempty" xml:"backend,omitempty"`
 }
 
 type CrodAnalysis struct {
@@ -32,32 +43,135 @@ type CrodAnalysis struct {
 
 var crodURL = getEnv("CROD_URL", "http://localhost:4000/api/claude/process")
 
+// allowedWSOrigins is a comma-separated allowlist of Origin header values
+// permitted to open a /process/ws connection, e.g.
+// "https://app.example.com,https://bridge.example.com". Left empty, only
+// same-origin requests (no Origin header, or one matching the request's
+// own Host) are allowed.
+var allowedWSOrigins = parseAllowedOrigins(getEnv("ALLOWED_WS_ORIGINS", ""))
+
+func parseAllowedOrigins(csv string) map[string]bool {
+    allowed := make(map[string]bool)
+    for _, origin := range strings.Split(csv, ",") {
+        if origin = strings.TrimSpace(origin); origin != "" {
+            allowed[origin] = true
+        }
+    }
+    return allowed
+}
+
+var wsUpgrader = websocket.Upgrader{
+    ReadBufferSize:  1024,
+    WriteBufferSize: 1024,
+    CheckOrigin: func(r *http.Request) bool {
+        origin := r.Header.Get("Origin")
+        if origin == "" {
+            return true
+        }
+        if allowedWSOrigins[origin] {
+            return true
+        }
+        u, err := url.Parse(origin)
+        return err == nil && u.Host == r.Host
+    },
+}
+
+// shutdownTimeout bounds how long graceful shutdown waits for in-flight
+// requests and jobs to drain before the process exits anyway.
+var shutdownTimeout = time.Duration(getEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 30)) * time.Second
+
 func main() {
-    http.HandleFunc("/process", handleProcess)
-    http.HandleFunc("/health", handleHealth)
-    
+    registerEnsemble("http", "noop", "grpc")
+
+    shutdownTracing := initTracing(context.Background())
+    defer shutdownTracing(context.Background())
+    startMetricsServer()
+    initJobStore()
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/process", handleProcess)
+    mux.HandleFunc("/process/stream", handleProcessStream)
+    mux.HandleFunc("/process/ws", handleProcessWS)
+    mux.HandleFunc("/process/batch", handleProcessBatch)
+    mux.HandleFunc("/jobs", handleJobsCreate)
+    mux.HandleFunc("/jobs/", handleJobsGet)
+    mux.HandleFunc("/health", handleHealth)
+
     port := getEnv("PORT", "9090")
+    server := &http.Server{Addr: ":" + port, Handler: mux}
+
     log.Printf("🧠 Claude-CROD Bridge starting on port %s", port)
     log.Printf("📡 CROD endpoint: %s", crodURL)
-    
-    log.Fatal(http.ListenAndServe(":"+port, nil))
+
+    go func() {
+        if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+            log.Fatalf("❌ server failed: %v", err)
+        }
+    }()
+
+    ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer stop()
+    <-ctx.Done()
+
+    log.Printf("🛑 shutting down, draining in-flight requests and jobs (timeout %s)...", shutdownTimeout)
+    shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+    defer cancel()
+    if err := server.Shutdown(shutdownCtx); err != nil {
+        log.Printf("❌ graceful shutdown failed: %v", err)
+    }
+
+    jobsDone := make(chan struct{})
+    go func() {
+        inFlightJobs.Wait()
+        close(jobsDone)
+    }()
+    select {
+    case <-jobsDone:
+    case <-shutdownCtx.Done():
+        // shutdownCtx shares its deadline with server.Shutdown above, so
+        // this fires once the *combined* drain has used up shutdownTimeout
+        // rather than granting jobs a second full timeout of their own.
+        log.Printf("⚠️ shutdown timeout exceeded, cancelling in-flight jobs")
+        cancelJobsCtx()
+        <-jobsDone
+    }
 }
 
 func handleProcess(w http.ResponseWriter, r *http.Request) {
-    if r.Method != "POST" {
+    ctx, span := tracer.Start(r.Context(), "handleProcess")
+    defer span.End()
+
+    // GET is accepted for query-param debug calls, e.g. curl smoke tests;
+    // POST is the normal content-negotiated path.
+    if r.Method != http.MethodPost && r.Method != http.MethodGet {
         http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
         return
     }
 
     var msg Message
-    if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+    if err := bindMessage(r, &msg); err != nil {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(fieldErrorsResponse(err))
+        return
+    }
+
+    backendName := msg.Backend
+    if backendName == "" {
+        backendName = defaultBackend
+    }
+    analyzer, err := getAnalyzer(backendName)
+    if err != nil {
         http.Error(w, err.Error(), http.StatusBadRequest)
         return
     }
 
-    // Send to CROD for analysis
-    analysis, err := analyzeWithCROD(msg)
+    start := time.Now()
+    analysis, err := analyzer.Analyze(ctx, msg)
+    crodLatencySeconds.Observe(time.Since(start).Seconds())
     if err != nil {
+        requestsTotal.WithLabelValues("error", backendName).Inc()
+        crodFailuresTotal.WithLabelValues(classifyFailure(err)).Inc()
         log.Printf("❌ CROD analysis failed: %v", err)
         // Return original message if CROD fails
         json.NewEncoder(w).Encode(map[string]interface{}{
@@ -68,8 +182,13 @@ func handleProcess(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    requestsTotal.WithLabelValues("ok", backendName).Inc()
+    crodConfidence.Observe(analysis.CrodAnalysis.Confidence)
+    neuronsActivatedGauge.Set(float64(analysis.CrodAnalysis.NeuronsActivated))
+    annotateAnalysis(span, analysis)
+
     // Log what CROD found
-    log.Printf("✅ CROD Analysis: mood=%s, intent=%s, confidence=%.2f", 
+    log.Printf("✅ CROD Analysis: mood=%s, intent=%s, confidence=%.2f",
         analysis.CrodAnalysis.Mood,
         analysis.CrodAnalysis.Intent,
         analysis.CrodAnalysis.Confidence)
@@ -86,34 +205,252 @@ func handleProcess(w http.ResponseWriter, r *http.Request) {
     json.NewEncoder(w).Encode(response)
 }
 
-func analyzeWithCROD(msg Message) (*CrodAnalysis, error) {
-    payload := map[string]string{
-        "message": msg.Content,
-        "context": msg.Context,
+// handleProcessStream serves /process/stream, the Server-Sent Events form
+// of /process, forwarding
+// each incremental CROD delta (mood, intent, neurons_activated updates) to
+// the client as soon as it arrives instead of blocking on the full analysis.
+func handleProcessStream(w http.ResponseWriter, r *http.Request) {
+    if r.Method != "POST" {
+        http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+        return
     }
-    
-    jsonData, err := json.Marshal(payload)
-    if err != nil {
-        return nil, err
+
+    var msg Message
+    if err := bindMessage(r, &msg); err != nil {
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusBadRequest)
+        json.NewEncoder(w).Encode(fieldErrorsResponse(err))
+        return
     }
 
-    resp, err := http.Post(crodURL, "application/json", bytes.NewBuffer(jsonData))
-    if err != nil {
-        return nil, err
+    flusher, ok := w.(http.Flusher)
+    if !ok {
+        http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+        return
     }
-    defer resp.Body.Close()
 
-    body, err := io.ReadAll(resp.Body)
+    w.Header().Set("Content-Type", "text/event-stream")
+    w.Header().Set("Cache-Control", "no-cache")
+    w.Header().Set("Connection", "keep-alive")
+
+    ctx := r.Context()
+    deltas, errs := streamAnalysis(ctx, msg)
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case delta, open := <-deltas:
+            if !open {
+                deltas = nil
+                break
+            }
+            payload, err := json.Marshal(delta)
+            if err != nil {
+                log.Printf("❌ failed to marshal CROD delta: %v", err)
+                continue
+            }
+            writeSSE(w, "", payload)
+            flusher.Flush()
+        case err, open := <-errs:
+            if !open {
+                errs = nil
+                break
+            }
+            if err != nil {
+                writeSSE(w, "error", []byte(err.Error()))
+                flusher.Flush()
+                return
+            }
+        }
+        if deltas == nil && errs == nil {
+            return
+        }
+    }
+}
+
+// handleProcessWS is the WebSocket equivalent of handleProcessStream: the
+// client sends a single Message frame and receives one frame per CROD delta.
+func handleProcessWS(w http.ResponseWriter, r *http.Request) {
+    conn, err := wsUpgrader.Upgrade(w, r, nil)
     if err != nil {
-        return nil, err
+        log.Printf("❌ websocket upgrade failed: %v", err)
+        return
+    }
+    defer conn.Close()
+
+    var msg Message
+    if err := conn.ReadJSON(&msg); err != nil {
+        conn.WriteJSON(map[string]string{"error": err.Error()})
+        return
+    }
+    // WebSocket frames are always JSON, so content-type dispatch doesn't
+    // apply, but the same struct validation (e.g. required Content) does.
+    if err := validate.Struct(&msg); err != nil {
+        conn.WriteJSON(fieldErrorsResponse(err))
+        return
     }
 
-    var analysis CrodAnalysis
-    if err := json.Unmarshal(body, &analysis); err != nil {
-        return nil, err
+    ctx := r.Context()
+    deltas, errs := streamAnalysis(ctx, msg)
+
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case delta, open := <-deltas:
+            if !open {
+                deltas = nil
+                break
+            }
+            if err := conn.WriteJSON(delta); err != nil {
+                log.Printf("❌ websocket write failed: %v", err)
+                return
+            }
+        case err, open := <-errs:
+            if !open {
+                errs = nil
+                break
+            }
+            if err != nil {
+                conn.WriteJSON(map[string]string{"error": err.Error()})
+                return
+            }
+        }
+        if deltas == nil && errs == nil {
+            return
+        }
     }
+}
+
+// streamAnalysis routes a streaming request through the same backend
+// registry /process uses. Only the "http" backend can genuinely stream
+// partial deltas (CROD's own chunked response); other backends run a
+// single Analyze call and emit its result as the one delta on the channel,
+// so selecting "grpc"/"noop"/"ensemble" on /process/stream and /process/ws
+// is honored rather than silently falling back to CROD HTTP.
+func streamAnalysis(ctx context.Context, msg Message) (<-chan CrodAnalysis, <-chan error) {
+    backendName := msg.Backend
+    if backendName == "" {
+        backendName = defaultBackend
+    }
+    if backendName == "http" {
+        return analyzeWithCROD(ctx, msg)
+    }
+
+    deltas := make(chan CrodAnalysis, 1)
+    errs := make(chan error, 1)
+    go func() {
+        defer close(deltas)
+        defer close(errs)
 
-    return &analysis, nil
+        analyzer, err := getAnalyzer(backendName)
+        if err != nil {
+            errs <- err
+            return
+        }
+        analysis, err := analyzer.Analyze(ctx, msg)
+        if err != nil {
+            errs <- err
+            return
+        }
+        deltas <- *analysis
+    }()
+    return deltas, errs
+}
+
+// analyzeWithCROD streams the CROD analysis as a series of deltas. CROD
+// emits one JSON object per partial update (mood/intent/neurons_activated
+// refinements) on the same response body, so we decode it incrementally
+// instead of buffering the whole response in memory.
+func analyzeWithCROD(ctx context.Context, msg Message) (<-chan CrodAnalysis, <-chan error) {
+    deltas := make(chan CrodAnalysis)
+    errs := make(chan error, 1)
+
+    go func() {
+        defer close(deltas)
+        defer close(errs)
+
+        ctx, span := tracer.Start(ctx, "analyzeWithCROD")
+        defer span.End()
+
+        payload := map[string]string{
+            "message": msg.Content,
+            "context": msg.Context,
+        }
+
+        jsonData, err := json.Marshal(payload)
+        if err != nil {
+            errs <- err
+            return
+        }
+
+        resp, err := postToCROD(ctx, jsonData)
+        if err != nil {
+            errs <- err
+            return
+        }
+        defer resp.Body.Close()
+
+        decoder := json.NewDecoder(resp.Body)
+        for decoder.More() {
+            var delta CrodAnalysis
+            if err := decoder.Decode(&delta); err != nil {
+                if err == io.EOF {
+                    return
+                }
+                errs <- err
+                return
+            }
+            select {
+            case deltas <- delta:
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+
+    return deltas, errs
+}
+
+// lastDelta drains a delta/error channel pair and returns the final delta
+// received, for callers that only care about the completed analysis.
+func lastDelta(deltas <-chan CrodAnalysis, errs <-chan error) (*CrodAnalysis, error) {
+    var last *CrodAnalysis
+    for deltas != nil || errs != nil {
+        select {
+        case delta, open := <-deltas:
+            if !open {
+                deltas = nil
+                continue
+            }
+            d := delta
+            last = &d
+        case err, open := <-errs:
+            if !open {
+                errs = nil
+                continue
+            }
+            if err != nil {
+                return nil, err
+            }
+        }
+    }
+    if last == nil {
+        return nil, io.ErrUnexpectedEOF
+    }
+    return last, nil
+}
+
+// writeSSE writes one Server-Sent Events frame. An empty event name emits
+// an unnamed "message" event, matching the default EventSource behaviour.
+func writeSSE(w http.ResponseWriter, event string, data []byte) {
+    if event != "" {
+        io.WriteString(w, "event: "+event+"\n")
+    }
+    io.WriteString(w, "data: ")
+    w.Write(data)
+    io.WriteString(w, "\n\n")
 }
 
 func handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -121,6 +458,7 @@ func handleHealth(w http.ResponseWriter, r *http.Request) {
     json.NewEncoder(w).Encode(map[string]string{
         "status": "healthy",
         "service": "claude-crod-bridge",
+        "crod_breaker_state": crodBreaker.State(),
     })
 }
 
@@ -129,4 +467,4 @@ func getEnv(key, defaultValue string) string {
         return value
     }
     return defaultValue
-}
\ No newline at end of file
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "log"
+    "net/http"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/promauto"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+    requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+        Name: "bridge_requests_total",
+        Help: "Total /process requests, by outcome status and analyzer backend.",
+    }, []string{"status", "backend"})
+
+    crodLatencySeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+        Name: "bridge_crod_latency_seconds",
+        Help: "Latency of CROD analysis calls.",
+        Buckets: prometheus.DefBuckets,
+    })
+
+    crodConfidence = promauto.NewHistogram(prometheus.HistogramOpts{
+        Name: "bridge_crod_confidence",
+        Help: "Confidence score reported by CROD analyses.",
+        Buckets: prometheus.LinearBuckets(0, 0.1, 11),
+    })
+
+    crodFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+        Name: "bridge_crod_failures_total",
+        Help: "Total failed CROD analyses, by failure reason.",
+    }, []string{"reason"})
+
+    neuronsActivatedGauge = promauto.NewGauge(prometheus.GaugeOpts{
+        Name: "bridge_crod_neurons_activated",
+        Help: "Neurons activated by the most recent CROD analysis.",
+    })
+)
+
+// classifyFailure buckets an analysis error into a small, stable set of
+// Prometheus label values so the cardinality doesn't explode with raw
+// error strings.
+func classifyFailure(err error) string {
+    switch {
+    case err == nil:
+        return "none"
+    case isCircuitBreakerError(err):
+        return "circuit_open"
+    case isTimeoutError(err):
+        return "timeout"
+    default:
+        return "upstream_error"
+    }
+}
+
+// metricsPort (METRICS_PORT, default 9091) is where /metrics is served, on
+// its own listener so scraping isn't mixed in with application traffic on
+// PORT.
+var metricsPort = getEnv("METRICS_PORT", "9091")
+
+func startMetricsServer() {
+    if metricsPort == "" {
+        return
+    }
+    mux := http.NewServeMux()
+    mux.Handle("/metrics", promhttp.Handler())
+    go func() {
+        log.Printf("📊 metrics listening on port %s", metricsPort)
+        if err := http.ListenAndServe(":"+metricsPort, mux); err != nil {
+            log.Printf("❌ metrics server stopped: %v", err)
+        }
+    }()
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+    "context"
+    "log"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+    "go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("claude-crod-bridge")
+
+// initTracing wires up an OTLP/gRPC exporter when OTEL_EXPORTER_OTLP_ENDPOINT
+// is set; otherwise tracing is a no-op so the bridge works unchanged in
+// environments without a collector.
+func initTracing(ctx context.Context) (shutdown func(context.Context) error) {
+    endpoint := getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+    if endpoint == "" {
+        otel.SetTextMapPropagator(propagation.TraceContext{})
+        return func(context.Context) error { return nil }
+    }
+
+    exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+    if err != nil {
+        log.Printf("❌ failed to start OTLP exporter: %v", err)
+        otel.SetTextMapPropagator(propagation.TraceContext{})
+        return func(context.Context) error { return nil }
+    }
+
+    res := resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName("claude-crod-bridge"))
+    tp := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(res),
+    )
+
+    otel.SetTracerProvider(tp)
+    otel.SetTextMapPropagator(propagation.TraceContext{})
+
+    return tp.Shutdown
+}
+
+// annotateAnalysis adds mood/intent/confidence attributes to the current
+// span once a CROD analysis has completed.
+func annotateAnalysis(span trace.Span, analysis *CrodAnalysis) {
+    if analysis == nil {
+        return
+    }
+    span.SetAttributes(
+        attribute.String("crod.mood", analysis.CrodAnalysis.Mood),
+        attribute.String("crod.intent", analysis.CrodAnalysis.Intent),
+        attribute.Float64("crod.confidence", analysis.CrodAnalysis.Confidence),
+    )
+}